@@ -0,0 +1,114 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BackupEntry records where an original file was copied to before it was
+// modified, along with the hashes needed to restore it safely later.
+type BackupEntry struct {
+	SourcePath     string `json:"source_path"`
+	BackupPath     string `json:"backup_path"`
+	OriginalSHA256 string `json:"original_sha256"`
+	UpdatedSHA256  string `json:"updated_sha256"`
+}
+
+// BackupManifest is written to <backupRoot>/manifest.json once an update run
+// finishes, and is the input consumed by runRestorer to undo the run.
+type BackupManifest struct {
+	CreatedAt      time.Time     `json:"created_at"`
+	ControllersDir string        `json:"controllers_dir"`
+	Entries        []BackupEntry `json:"entries"`
+}
+
+// backupRun tracks the timestamped backup tree for a single runUpdater
+// invocation, making sure each original file is only copied once no matter
+// how many mappings touch it.
+type backupRun struct {
+	root    string
+	indexOf map[string]int
+	entries []BackupEntry
+}
+
+// newBackupRun creates a fresh timestamped directory under baseDir, e.g.
+// backups/migro_20060102_150405, to hold originals for this run.
+func newBackupRun(baseDir string) (*backupRun, error) {
+	root := filepath.Join(baseDir, fmt.Sprintf("migro_%s", time.Now().Format("20060102_150405")))
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+	return &backupRun{root: root, indexOf: make(map[string]int)}, nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// backupFile copies absPath into the backup tree under relPath the first
+// time it is seen; later calls for the same absPath are no-ops. It returns
+// the number of bytes written so callers can accumulate BytesBackedUp.
+func (b *backupRun) backupFile(relPath, absPath string) (int64, error) {
+	if _, ok := b.indexOf[absPath]; ok {
+		return 0, nil
+	}
+
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return 0, err
+	}
+
+	backupPath := filepath.Join(b.root, relPath)
+	if err := os.MkdirAll(filepath.Dir(backupPath), 0755); err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+		return 0, err
+	}
+
+	b.indexOf[absPath] = len(b.entries)
+	b.entries = append(b.entries, BackupEntry{
+		SourcePath:     absPath,
+		BackupPath:     backupPath,
+		OriginalSHA256: sha256Hex(data),
+	})
+	return int64(len(data)), nil
+}
+
+// recordUpdate stores the hash of absPath as it stands immediately after a
+// modification was written, so runRestorer can later tell whether the file
+// was hand-edited again before a restore is attempted.
+func (b *backupRun) recordUpdate(absPath string, data []byte) {
+	idx, ok := b.indexOf[absPath]
+	if !ok {
+		return
+	}
+	b.entries[idx].UpdatedSHA256 = sha256Hex(data)
+}
+
+// writeManifest persists the manifest.json for this run into its backup
+// root and returns the manifest's path.
+func (b *backupRun) writeManifest(controllersDir string) (string, error) {
+	manifest := BackupManifest{
+		CreatedAt:      time.Now(),
+		ControllersDir: controllersDir,
+		Entries:        b.entries,
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	manifestPath := filepath.Join(b.root, "manifest.json")
+	if err := os.WriteFile(manifestPath, data, 0644); err != nil {
+		return "", err
+	}
+	return manifestPath, nil
+}
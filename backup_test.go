@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupRunBacksUpEachFileOnce(t *testing.T) {
+	srcDir := t.TempDir()
+	backupDir := t.TempDir()
+
+	path := filepath.Join(srcDir, "WidgetsController.cs")
+	original := []byte("original contents")
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	run, err := newBackupRun(backupDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := run.backupFile("WidgetsController.cs", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(original)) {
+		t.Errorf("backupFile returned %d bytes, want %d", n, len(original))
+	}
+
+	// A second call for the same source path must be a no-op.
+	n, err = run.backupFile("WidgetsController.cs", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("second backupFile call returned %d bytes, want 0", n)
+	}
+	if len(run.entries) != 1 {
+		t.Fatalf("got %d backup entries, want 1", len(run.entries))
+	}
+
+	entry := run.entries[0]
+	if entry.OriginalSHA256 != sha256Hex(original) {
+		t.Error("OriginalSHA256 mismatch")
+	}
+
+	updated := []byte("updated contents")
+	run.recordUpdate(path, updated)
+	if run.entries[0].UpdatedSHA256 != sha256Hex(updated) {
+		t.Error("UpdatedSHA256 mismatch")
+	}
+}
+
+func TestBackupRunWriteManifest(t *testing.T) {
+	backupDir := t.TempDir()
+	run, err := newBackupRun(backupDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srcDir := t.TempDir()
+	path := filepath.Join(srcDir, "WidgetsController.cs")
+	if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run.backupFile("WidgetsController.cs", path); err != nil {
+		t.Fatal(err)
+	}
+
+	manifestPath, err := run.writeManifest(srcDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(manifestPath); err != nil {
+		t.Fatalf("manifest not written: %v", err)
+	}
+
+	manifest, err := readManifest(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Entries) != 1 || manifest.ControllersDir != srcDir {
+		t.Errorf("manifest = %+v", manifest)
+	}
+}
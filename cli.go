@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+)
+
+// Flags bound by the cobra command tree below. They stand in for the values
+// handleScan/handleUpdate/handleRestore used to collect via promptForInput.
+var (
+	flagControllersDir string
+	flagCsvPath        string
+	flagPreview        bool
+	flagOverwrite      bool
+	flagWorkers        int
+	flagLogDir         string
+	flagManifestPath   string
+	flagFilesFrom      string
+)
+
+// signalContext returns a context that's cancelled on SIGINT, so a long scan
+// or update can stop cleanly between files instead of being killed mid-write.
+func signalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+// newRootCmd builds the migro command tree. With no subcommand it falls back
+// to the original interactive menu; scan/update/restore/completion are thin
+// RunE wrappers around the existing package-level business logic, which all
+// pull their RunConfig off the command's context.
+func newRootCmd() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "migro",
+		Short: "Scan and update [Authorize] attributes on C# controllers",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			cfg := DefaultRunConfig()
+			cfg.Logger = newFileLogger(flagLogDir)
+			cmd.SetContext(WithConfig(cmd.Context(), cfg))
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			ConfigFromContext(cmd.Context()).Logger.Close()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runInteractive(cmd.Context())
+			return nil
+		},
+	}
+
+	root.PersistentFlags().StringVar(&flagLogDir, "log-dir", "logs", "directory to write the run's log file into")
+	root.AddCommand(newScanCmd(), newUpdateCmd(), newRestoreCmd(), newCompletionCmd())
+	return root
+}
+
+func newScanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Scan controller files and generate a CSV mapping",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flagCsvPath == "" {
+				return fmt.Errorf("--csv is required")
+			}
+
+			cfg := ConfigFromContext(cmd.Context())
+			cfg.Workers = flagWorkers
+			ctx := WithConfig(cmd.Context(), cfg)
+
+			if flagFilesFrom != "" {
+				paths, err := readPathsFrom(flagFilesFrom)
+				if err != nil {
+					return fmt.Errorf("reading --files-from %q: %w", flagFilesFrom, err)
+				}
+				RunScannerFiles(ctx, paths, flagCsvPath)
+				return nil
+			}
+
+			if flagControllersDir == "" {
+				return fmt.Errorf("--controllers is required (or use --files-from)")
+			}
+			RunScanner(ctx, flagControllersDir, flagCsvPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&flagControllersDir, "controllers", "", "path to the controllers directory")
+	cmd.Flags().StringVar(&flagCsvPath, "csv", "", "path for the output CSV file")
+	cmd.Flags().IntVar(&flagWorkers, "workers", defaultScanWorkers(), "number of concurrent scan workers")
+	cmd.Flags().StringVar(&flagFilesFrom, "files-from", "", "read .cs paths to scan from this file, one per line (\"-\" for stdin), instead of walking --controllers")
+	return cmd
+}
+
+func newUpdateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Apply [Authorize] attributes from a CSV mapping onto controllers",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flagCsvPath == "" {
+				return fmt.Errorf("--csv is required")
+			}
+			if flagControllersDir == "" {
+				return fmt.Errorf("--controllers is required")
+			}
+
+			cfg := ConfigFromContext(cmd.Context())
+			cfg.Preview = flagPreview
+			cfg.Overwrite = flagOverwrite
+			RunUpdater(WithConfig(cmd.Context(), cfg), flagCsvPath, flagControllersDir)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&flagCsvPath, "csv", "", "path to the input CSV file")
+	cmd.Flags().StringVar(&flagControllersDir, "controllers", "", "path to the controllers directory")
+	cmd.Flags().BoolVar(&flagPreview, "preview", false, "show changes without modifying files")
+	cmd.Flags().BoolVar(&flagOverwrite, "overwrite", false, "automatically replace existing attributes")
+	return cmd
+}
+
+func newRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore originals recorded in a backup manifest",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flagManifestPath == "" {
+				return fmt.Errorf("--manifest is required")
+			}
+			RunRestorer(cmd.Context(), flagManifestPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&flagManifestPath, "manifest", "", "path to the backup manifest.json")
+	return cmd
+}
+
+func newCompletionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:                   "completion [bash|zsh|fish|powershell]",
+		Short:                 "Generate a shell completion script",
+		DisableFlagsInUseLine: true,
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		// The generated script is written straight to os.Stdout, so this
+		// overrides the root's PersistentPreRun rather than inheriting it:
+		// newFileLogger's startup banner also goes to stdout, and would land
+		// ahead of the script and corrupt `source <(migro completion bash)`.
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			cmd.SetContext(WithConfig(cmd.Context(), DefaultRunConfig()))
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch args[0] {
+			case "bash":
+				return cmd.Root().GenBashCompletion(os.Stdout)
+			case "zsh":
+				return cmd.Root().GenZshCompletion(os.Stdout)
+			case "fish":
+				return cmd.Root().GenFishCompletion(os.Stdout, true)
+			case "powershell":
+				return cmd.Root().GenPowerShellCompletionWithDesc(os.Stdout)
+			default:
+				return fmt.Errorf("unsupported shell: %s", args[0])
+			}
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import "context"
+
+// RunConfig bundles the knobs that used to live as package-level globals or
+// had to be threaded through as extra parameters: where logs go, how many
+// scan workers to run, which update mode is active, and the attribute
+// vocabulary the scanner/updater recognize. Scanner and updater runs each
+// get their own RunConfig instead of mutating shared state, so they can run
+// concurrently (e.g. from tests) without stepping on each other.
+type RunConfig struct {
+	Logger               Logger
+	Workers              int
+	Preview              bool
+	Overwrite            bool
+	HTTPAttributes       []string
+	DefaultAuthorizeAttr string
+}
+
+// DefaultRunConfig returns the configuration migro falls back to when none
+// was attached to the context: a stdout-only logger, one worker per CPU,
+// and the built-in HTTP attribute and default [Authorize] templates.
+// Callers that want the usual on-disk log (e.g. the cobra commands in
+// cli.go) replace cfg.Logger with newFileLogger before running anything.
+func DefaultRunConfig() RunConfig {
+	return RunConfig{
+		Logger:               newStdoutLogger(),
+		Workers:              defaultScanWorkers(),
+		HTTPAttributes:       httpAttributes,
+		DefaultAuthorizeAttr: `[Authorize(Roles = "")]`,
+	}
+}
+
+type runConfigKey struct{}
+
+// WithConfig attaches cfg to ctx, retrievable later with ConfigFromContext.
+func WithConfig(ctx context.Context, cfg RunConfig) context.Context {
+	return context.WithValue(ctx, runConfigKey{}, cfg)
+}
+
+// ConfigFromContext returns the RunConfig carried by ctx, or
+// DefaultRunConfig() if none was ever attached.
+func ConfigFromContext(ctx context.Context) RunConfig {
+	if cfg, ok := ctx.Value(runConfigKey{}).(RunConfig); ok {
+		return cfg
+	}
+	return DefaultRunConfig()
+}
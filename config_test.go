@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestConfigFromContextDefaultsWhenUnset(t *testing.T) {
+	cfg := ConfigFromContext(context.Background())
+	if cfg.Workers < 1 {
+		t.Errorf("Workers = %d, want >= 1", cfg.Workers)
+	}
+	if cfg.Logger == nil {
+		t.Error("Logger is nil")
+	}
+}
+
+func TestWithConfigRoundTrip(t *testing.T) {
+	cfg := DefaultRunConfig()
+	cfg.Workers = 7
+	cfg.Overwrite = true
+	ctx := WithConfig(context.Background(), cfg)
+
+	got := ConfigFromContext(ctx)
+	if got.Workers != 7 || !got.Overwrite {
+		t.Errorf("got %+v, want Workers=7 Overwrite=true", got)
+	}
+}
+
+// TestConfigsDontShareStateAcrossContexts is the thing context-threading was
+// for: two RunConfigs attached to independent contexts must stay independent,
+// unlike the package-level globals they replaced.
+func TestConfigsDontShareStateAcrossContexts(t *testing.T) {
+	a := WithConfig(context.Background(), RunConfig{Workers: 1})
+	b := WithConfig(context.Background(), RunConfig{Workers: 2})
+
+	if w := ConfigFromContext(a).Workers; w != 1 {
+		t.Errorf("a.Workers = %d, want 1", w)
+	}
+	if w := ConfigFromContext(b).Workers; w != 2 {
+		t.Errorf("b.Workers = %d, want 2", w)
+	}
+}
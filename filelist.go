@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// readPathsFrom reads one path per line from listPath (or stdin, when
+// listPath is "-"), skipping blank lines and "#" comments, the same
+// convention restic's --files-from flag uses.
+func readPathsFrom(listPath string) ([]string, error) {
+	var r io.Reader
+	if listPath == "-" {
+		r = os.Stdin
+	} else {
+		file, err := os.Open(listPath)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	var paths []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadPathsFromSkipsBlanksAndComments(t *testing.T) {
+	dir := t.TempDir()
+	listPath := filepath.Join(dir, "files.txt")
+	content := "controllers/A.cs\n\n# a comment\ncontrollers/B.cs\n  \n"
+	if err := os.WriteFile(listPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readPathsFrom(listPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"controllers/A.cs", "controllers/B.cs"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestReadPathsFromMissingFile(t *testing.T) {
+	if _, err := readPathsFrom(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("expected an error for a missing list file")
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Logger is the logging surface RunScanner/RunUpdater/RunRestorer pull off
+// the context, replacing the old package-level logFile/writeLog pair so
+// independent runs no longer share mutable log state.
+type Logger interface {
+	// Log prints message to stdout and the log sink, prefixed with a timestamp.
+	Log(message string)
+	// LogOnly writes message to the log sink only, for detail that would be
+	// noisy on the terminal (e.g. once-per-file scan progress).
+	LogOnly(message string)
+	// StdoutWriter returns an io.Writer onto stdout that shares Log/LogOnly's
+	// lock, so a scanFiles progress bar can redraw through it (via
+	// pb.ProgressBar.SetWriter) without its own redraws interleaving with a
+	// worker's log line mid-write.
+	StdoutWriter() io.Writer
+	// Close releases any underlying file handle.
+	Close() error
+}
+
+// syncWriter serializes writes to w behind mu, so unrelated writers sharing
+// the same terminal (a Logger and a pb.ProgressBar) can't interleave.
+type syncWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// fileLogger writes to stdout plus an optional log file, serializing both
+// so concurrent scan workers can't interleave their output.
+type fileLogger struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// newFileLogger creates logDir/migro_<timestamp>.log and returns a Logger
+// backed by it. If logDir is empty it defaults to "logs"; if the directory
+// can't be created, it falls back to the current directory, same as the
+// original setupLogger did. If the file still can't be created, the
+// returned Logger still works, it just only writes to stdout.
+func newFileLogger(logDir string) Logger {
+	if logDir == "" {
+		logDir = "logs"
+	}
+
+	l := &fileLogger{}
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not create log directory: %v\n", err)
+		logDir = "."
+	}
+
+	logFilePath := filepath.Join(logDir, fmt.Sprintf("migro_%s.log", time.Now().Format("20060102_150405")))
+	file, err := os.Create(logFilePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not create log file: %v\n", err)
+		return l
+	}
+
+	l.file = file
+	l.LogOnly(fmt.Sprintf("Log file created: %s", logFilePath))
+	l.LogOnly("Migro - Execution Started")
+	l.LogOnly("=" + strings.Repeat("=", 50))
+	fmt.Printf("📝 Detailed log will be saved to: %s\n\n", logFilePath)
+	return l
+}
+
+func (l *fileLogger) Log(message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	line := fmt.Sprintf("[%s] %s\n", time.Now().Format("2006-01-02 15:04:05"), message)
+	fmt.Print(line)
+	if l.file != nil {
+		l.file.WriteString(line)
+	}
+}
+
+func (l *fileLogger) LogOnly(message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	line := fmt.Sprintf("[%s] %s\n", time.Now().Format("2006-01-02 15:04:05"), message)
+	if l.file != nil {
+		l.file.WriteString(line)
+	}
+}
+
+func (l *fileLogger) StdoutWriter() io.Writer {
+	return &syncWriter{mu: &l.mu, w: os.Stdout}
+}
+
+func (l *fileLogger) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// stdoutLogger writes to stdout only, with no file and nothing to close.
+// It's DefaultRunConfig's Logger, so building a default RunConfig never has
+// the side effect of creating a log file that a caller is just going to
+// replace with newFileLogger anyway.
+type stdoutLogger struct {
+	mu sync.Mutex
+}
+
+func newStdoutLogger() Logger {
+	return &stdoutLogger{}
+}
+
+func (l *stdoutLogger) Log(message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Printf("[%s] %s\n", time.Now().Format("2006-01-02 15:04:05"), message)
+}
+
+func (l *stdoutLogger) LogOnly(message string) {}
+
+func (l *stdoutLogger) StdoutWriter() io.Writer {
+	return &syncWriter{mu: &l.mu, w: os.Stdout}
+}
+
+func (l *stdoutLogger) Close() error { return nil }
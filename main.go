@@ -2,15 +2,20 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"encoding/csv"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/cheggaaa/pb/v3"
 )
 
 type CsvMapping struct {
@@ -20,21 +25,34 @@ type CsvMapping struct {
 	Attribute  string
 }
 
+// ScannerStats is shared across the scan worker pool, so every counter is
+// mutated through the atomic package instead of plain ++.
 type ScannerStats struct {
-	TotalFiles     int
-	FilesProcessed int
-	FilesSkipped   int
-	MethodsFound   int
-	Errors         int
+	TotalFiles     int64
+	FilesProcessed int64
+	FilesSkipped   int64
+	MethodsFound   int64
+	Errors         int64
 	StartTime      time.Time
 }
 
+// defaultScanWorkers is how many goroutines scanDirectory dispatches files
+// to when the caller doesn't request a specific pool size.
+func defaultScanWorkers() int {
+	if n := runtime.NumCPU(); n > 0 {
+		return n
+	}
+	return 1
+}
+
 type UpdaterStats struct {
 	TotalFiles         int
 	FilesModified      int
 	FilesSkipped       int
 	AttributesAdded    int
 	AttributesReplaced int
+	FilesBackedUp      int
+	BytesBackedUp      int64
 	Errors             int
 	StartTime          time.Time
 }
@@ -47,54 +65,7 @@ var httpAttributes = []string{
 	"[HttpPatch]", "[HttpPatch(",
 }
 
-var logFile *os.File
-
-func setupLogger() {
-	logDir := "logs"
-	if err := os.MkdirAll(logDir, 0755); err != nil {
-		log.Printf("Warning: Could not create log directory: %v", err)
-		logDir = "."
-	}
-
-	logFileName := fmt.Sprintf("migro_%s.log", time.Now().Format("20060102_150405"))
-	logFilePath := filepath.Join(logDir, logFileName)
-
-	var err error
-	logFile, err = os.Create(logFilePath)
-	if err != nil {
-		log.Printf("Warning: Could not create log file: %v", err)
-	} else {
-		writeLogOnly(fmt.Sprintf("Log file created: %s", logFilePath))
-		writeLogOnly("Migro - Execution Started")
-		writeLogOnly("=" + strings.Repeat("=", 50))
-	}
-	fmt.Printf("📝 Detailed log will be saved to: %s\n\n", logFilePath)
-}
-
-func closeLogger() {
-	if logFile != nil {
-		logFile.Close()
-	}
-}
-
-func writeLog(message string) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	logMessage := fmt.Sprintf("[%s] %s\n", timestamp, message)
-	fmt.Print(logMessage)
-	if logFile != nil {
-		logFile.WriteString(logMessage)
-	}
-}
-
-func writeLogOnly(message string) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	logMessage := fmt.Sprintf("[%s] %s\n", timestamp, message)
-	if logFile != nil {
-		logFile.WriteString(logMessage)
-	}
-}
-
-func isHttpAttribute(line string) bool {
+func isHttpAttribute(line string, httpAttributes []string) bool {
 	line = strings.TrimSpace(line)
 	for _, attr := range httpAttributes {
 		if strings.HasPrefix(line, attr) {
@@ -137,7 +108,11 @@ func extractMethodName(line string) string {
 	return ""
 }
 
-func scanControllerFile(filePath string, stats *ScannerStats) ([]CsvMapping, error) {
+// ScanControllerFile scans a single .cs file for HTTP-attributed methods.
+// The HTTP attribute vocabulary comes from the RunConfig on ctx.
+func ScanControllerFile(ctx context.Context, filePath string, stats *ScannerStats) ([]CsvMapping, error) {
+	cfg := ConfigFromContext(ctx)
+
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
@@ -156,7 +131,7 @@ func scanControllerFile(filePath string, stats *ScannerStats) ([]CsvMapping, err
 
 	filename := filepath.Base(filePath)
 	controllerName := extractControllerName(filename)
-	writeLogOnly(fmt.Sprintf("Scanning file: %s (Controller: %s)", filename, controllerName))
+	cfg.Logger.LogOnly(fmt.Sprintf("Scanning file: %s (Controller: %s)", filename, controllerName))
 
 	for i, line := range lines {
 		methodName := extractMethodName(line)
@@ -172,7 +147,7 @@ func scanControllerFile(filePath string, stats *ScannerStats) ([]CsvMapping, err
 				continue
 			}
 			if strings.HasPrefix(trimmedLine, "[") {
-				if isHttpAttribute(trimmedLine) {
+				if isHttpAttribute(trimmedLine, cfg.HTTPAttributes) {
 					hasHttpAttribute = true
 				}
 				if isAuthorizeAttribute(trimmedLine) {
@@ -184,7 +159,7 @@ func scanControllerFile(filePath string, stats *ScannerStats) ([]CsvMapping, err
 		}
 
 		if hasHttpAttribute {
-			attribute := `[Authorize(Roles = "")]`
+			attribute := cfg.DefaultAuthorizeAttr
 			if authorizeAttribute != "" {
 				attribute = authorizeAttribute
 			}
@@ -194,42 +169,127 @@ func scanControllerFile(filePath string, stats *ScannerStats) ([]CsvMapping, err
 				Method:     methodName,
 				Attribute:  attribute,
 			})
-			writeLogOnly(fmt.Sprintf("Found method: %s in %s", methodName, filename))
-			stats.MethodsFound++
+			cfg.Logger.LogOnly(fmt.Sprintf("Found method: %s in %s", methodName, filename))
+			atomic.AddInt64(&stats.MethodsFound, 1)
 		}
 	}
 	return methods, nil
 }
 
-func scanDirectory(dirPath string, stats *ScannerStats) ([]CsvMapping, error) {
-	var allMethods []CsvMapping
+// findCsFiles walks dirPath once up front to enumerate the .cs paths that
+// scanDirectory will then hand out to its worker pool.
+func findCsFiles(ctx context.Context, dirPath string, stats *ScannerStats) ([]string, error) {
+	logger := ConfigFromContext(ctx).Logger
+
+	var paths []string
 	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			writeLog(fmt.Sprintf("ERROR: Accessing path %s: %v", path, err))
-			stats.Errors++
+			logger.Log(fmt.Sprintf("ERROR: Accessing path %s: %v", path, err))
+			atomic.AddInt64(&stats.Errors, 1)
 			return nil
 		}
 		if !info.IsDir() && strings.HasSuffix(strings.ToLower(info.Name()), ".cs") {
-			stats.TotalFiles++
-			methods, err := scanControllerFile(path, stats)
-			if err != nil {
-				writeLog(fmt.Sprintf("ERROR: Scanning file %s: %v", path, err))
-				stats.FilesSkipped++
-				stats.Errors++
-				return nil
-			}
-			if len(methods) > 0 {
-				allMethods = append(allMethods, methods...)
-				stats.FilesProcessed++
-				writeLog(fmt.Sprintf("✅ Processed: %s (%d methods found)", info.Name(), len(methods)))
-			} else {
-				writeLog(fmt.Sprintf("ℹ️  Skipped: %s (no HTTP methods found)", info.Name()))
-				stats.FilesSkipped++
-			}
+			paths = append(paths, path)
 		}
 		return nil
 	})
-	return allMethods, err
+	return paths, err
+}
+
+// scanFile scans a single path and reports the result on resultsCh, always
+// incrementing bar exactly once regardless of outcome.
+func scanFile(ctx context.Context, path string, stats *ScannerStats, resultsCh chan<- []CsvMapping, bar *pb.ProgressBar) {
+	defer bar.Increment()
+	logger := ConfigFromContext(ctx).Logger
+
+	methods, err := ScanControllerFile(ctx, path, stats)
+	name := filepath.Base(path)
+	if err != nil {
+		logger.Log(fmt.Sprintf("ERROR: Scanning file %s: %v", path, err))
+		atomic.AddInt64(&stats.FilesSkipped, 1)
+		atomic.AddInt64(&stats.Errors, 1)
+		return
+	}
+
+	if len(methods) > 0 {
+		atomic.AddInt64(&stats.FilesProcessed, 1)
+		logger.Log(fmt.Sprintf("✅ Processed: %s (%d methods found)", name, len(methods)))
+		resultsCh <- methods
+	} else {
+		logger.Log(fmt.Sprintf("ℹ️  Skipped: %s (no HTTP methods found)", name))
+		atomic.AddInt64(&stats.FilesSkipped, 1)
+	}
+}
+
+// scanDirectory enumerates every .cs file under dirPath up front, then hands
+// them to scanFiles.
+func scanDirectory(ctx context.Context, dirPath string, stats *ScannerStats) ([]CsvMapping, error) {
+	paths, err := findCsFiles(ctx, dirPath, stats)
+	if err != nil {
+		return nil, err
+	}
+	return scanFiles(ctx, paths, stats)
+}
+
+// scanFiles fans paths out across cfg.Workers goroutines and aggregates
+// their results. It's the shared core behind scanDirectory (paths come from
+// walking a directory) and RunScannerFiles (paths come from --files-from).
+// A cancelled ctx (e.g. on SIGINT) stops dispatching new files; files
+// already in flight are allowed to finish so their output isn't corrupted.
+func scanFiles(ctx context.Context, paths []string, stats *ScannerStats) ([]CsvMapping, error) {
+	cfg := ConfigFromContext(ctx)
+	workers := cfg.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	atomic.StoreInt64(&stats.TotalFiles, int64(len(paths)))
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	// SetWriter before Start so the bar's own redraw ticker writes through
+	// the same lock as cfg.Logger.Log, instead of racing it on os.Stdout.
+	bar := pb.New(len(paths))
+	bar.SetWriter(cfg.Logger.StdoutWriter())
+	bar.Start()
+	defer bar.Finish()
+
+	jobs := make(chan string)
+	resultsCh := make(chan []CsvMapping)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				scanFile(ctx, path, stats, resultsCh, bar)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, path := range paths {
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- path:
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(resultsCh)
+	}()
+
+	var allMethods []CsvMapping
+	for methods := range resultsCh {
+		allMethods = append(allMethods, methods...)
+	}
+	return allMethods, ctx.Err()
 }
 
 func writeCsv(methods []CsvMapping, outputPath string) error {
@@ -254,55 +314,94 @@ func writeCsv(methods []CsvMapping, outputPath string) error {
 	return nil
 }
 
-func printScannerStats(stats ScannerStats) {
+func printScannerStats(ctx context.Context, stats ScannerStats) {
+	logger := ConfigFromContext(ctx).Logger
 	duration := time.Since(stats.StartTime)
-	writeLog("=" + strings.Repeat("=", 50))
-	writeLog("SCAN SUMMARY")
-	writeLog("=" + strings.Repeat("=", 50))
-	writeLog(fmt.Sprintf("Execution Time: %v", duration))
-	writeLog(fmt.Sprintf("Total Files Found: %d", stats.TotalFiles))
-	writeLog(fmt.Sprintf("Files Processed: %d", stats.FilesProcessed))
-	writeLog(fmt.Sprintf("Files Skipped: %d", stats.FilesSkipped))
-	writeLog(fmt.Sprintf("Methods Found: %d", stats.MethodsFound))
-	writeLog(fmt.Sprintf("Errors Encountered: %d", stats.Errors))
-	writeLog("=" + strings.Repeat("=", 50))
+	logger.Log("=" + strings.Repeat("=", 50))
+	logger.Log("SCAN SUMMARY")
+	logger.Log("=" + strings.Repeat("=", 50))
+	logger.Log(fmt.Sprintf("Execution Time: %v", duration))
+	logger.Log(fmt.Sprintf("Total Files Found: %d", stats.TotalFiles))
+	logger.Log(fmt.Sprintf("Files Processed: %d", stats.FilesProcessed))
+	logger.Log(fmt.Sprintf("Files Skipped: %d", stats.FilesSkipped))
+	logger.Log(fmt.Sprintf("Methods Found: %d", stats.MethodsFound))
+	logger.Log(fmt.Sprintf("Errors Encountered: %d", stats.Errors))
+	logger.Log("=" + strings.Repeat("=", 50))
 }
 
-func runScanner(controllersDir, outputCsvPath string) {
+// RunScanner walks controllersDir for .cs files and writes a CSV mapping of
+// every HTTP-attributed method found to outputCsvPath. Workers and the HTTP
+// attribute vocabulary come from the RunConfig on ctx; a cancelled ctx stops
+// the scan early.
+func RunScanner(ctx context.Context, controllersDir, outputCsvPath string) {
+	cfg := ConfigFromContext(ctx)
 	stats := ScannerStats{StartTime: time.Now()}
-	writeLog("Starting Scanner...")
-	writeLog(fmt.Sprintf("Controllers Directory: %s", controllersDir))
-	writeLog(fmt.Sprintf("Output CSV Path: %s", outputCsvPath))
-	writeLog("-" + strings.Repeat("-", 50))
+	cfg.Logger.Log("Starting Scanner...")
+	cfg.Logger.Log(fmt.Sprintf("Controllers Directory: %s", controllersDir))
+	cfg.Logger.Log(fmt.Sprintf("Output CSV Path: %s", outputCsvPath))
+	cfg.Logger.Log(fmt.Sprintf("Workers: %d", cfg.Workers))
+	cfg.Logger.Log("-" + strings.Repeat("-", 50))
 
 	if _, err := os.Stat(controllersDir); os.IsNotExist(err) {
-		writeLog(fmt.Sprintf("FATAL ERROR: Controllers directory does not exist: %s", controllersDir))
+		cfg.Logger.Log(fmt.Sprintf("FATAL ERROR: Controllers directory does not exist: %s", controllersDir))
 		stats.Errors++
-		printScannerStats(stats)
+		printScannerStats(ctx, stats)
 		return
 	}
 
-	methods, err := scanDirectory(controllersDir, &stats)
+	methods, err := scanDirectory(ctx, controllersDir, &stats)
+	if err != nil {
+		cfg.Logger.Log(fmt.Sprintf("ERROR: Failed to scan directory: %v", err))
+		stats.Errors++
+	}
+
+	finishScan(ctx, methods, outputCsvPath, &stats)
+}
+
+// RunScannerFiles scans exactly the given paths instead of walking a
+// directory, for callers that already know which files changed (e.g.
+// --files-from fed by `git diff --name-only`).
+func RunScannerFiles(ctx context.Context, paths []string, outputCsvPath string) {
+	cfg := ConfigFromContext(ctx)
+	stats := ScannerStats{StartTime: time.Now()}
+	cfg.Logger.Log("Starting Scanner (explicit file list)...")
+	cfg.Logger.Log(fmt.Sprintf("Files Provided: %d", len(paths)))
+	cfg.Logger.Log(fmt.Sprintf("Output CSV Path: %s", outputCsvPath))
+	cfg.Logger.Log(fmt.Sprintf("Workers: %d", cfg.Workers))
+	cfg.Logger.Log("-" + strings.Repeat("-", 50))
+
+	methods, err := scanFiles(ctx, paths, &stats)
 	if err != nil {
-		writeLog(fmt.Sprintf("ERROR: Failed to scan directory: %v", err))
+		cfg.Logger.Log(fmt.Sprintf("ERROR: Failed to scan files: %v", err))
 		stats.Errors++
 	}
 
+	finishScan(ctx, methods, outputCsvPath, &stats)
+}
+
+// finishScan is the tail shared by RunScanner and RunScannerFiles: write the
+// CSV if anything was found, then print the summary.
+func finishScan(ctx context.Context, methods []CsvMapping, outputCsvPath string, stats *ScannerStats) {
+	logger := ConfigFromContext(ctx).Logger
 	if len(methods) == 0 {
-		writeLog("WARNING: No HTTP methods found in any controller files.")
+		logger.Log("WARNING: No HTTP methods found in any controller files.")
 	} else {
-		writeLog(fmt.Sprintf("Found %d HTTP methods across %d controller files", len(methods), stats.FilesProcessed))
+		logger.Log(fmt.Sprintf("Found %d HTTP methods across %d controller files", len(methods), stats.FilesProcessed))
 		if err := writeCsv(methods, outputCsvPath); err != nil {
-			writeLog(fmt.Sprintf("FATAL ERROR: Failed to write CSV file: %v", err))
+			logger.Log(fmt.Sprintf("FATAL ERROR: Failed to write CSV file: %v", err))
 			stats.Errors++
 		} else {
-			writeLog(fmt.Sprintf("✅ Successfully generated CSV file: %s", outputCsvPath))
+			logger.Log(fmt.Sprintf("✅ Successfully generated CSV file: %s", outputCsvPath))
 		}
 	}
-	printScannerStats(stats)
+	printScannerStats(ctx, *stats)
 }
 
-func readCsvMappings(csvPath string) ([]CsvMapping, error) {
+// ReadCsvMappings reads the filename/controller/method/attribute rows
+// written by RunScanner, skipping the header row.
+func ReadCsvMappings(ctx context.Context, csvPath string) ([]CsvMapping, error) {
+	logger := ConfigFromContext(ctx).Logger
+
 	file, err := os.Open(csvPath)
 	if err != nil {
 		return nil, err
@@ -322,7 +421,7 @@ func readCsvMappings(csvPath string) ([]CsvMapping, error) {
 			continue
 		}
 		if len(row) < 4 {
-			writeLog(fmt.Sprintf("WARNING: Skipping incomplete row in CSV: %v", row))
+			logger.Log(fmt.Sprintf("WARNING: Skipping incomplete row in CSV: %v", row))
 			continue
 		}
 		mappings = append(mappings, CsvMapping{
@@ -335,54 +434,80 @@ func readCsvMappings(csvPath string) ([]CsvMapping, error) {
 	return mappings, nil
 }
 
-func printUpdaterStats(stats UpdaterStats) {
+func printUpdaterStats(ctx context.Context, stats UpdaterStats) {
+	logger := ConfigFromContext(ctx).Logger
 	duration := time.Since(stats.StartTime)
-	writeLog("=" + strings.Repeat("=", 50))
-	writeLog("UPDATE SUMMARY")
-	writeLog("=" + strings.Repeat("=", 50))
-	writeLog(fmt.Sprintf("Execution Time: %v", duration))
-	writeLog(fmt.Sprintf("Total Mappings Processed: %d", stats.TotalFiles))
-	writeLog(fmt.Sprintf("Files Modified: %d", stats.FilesModified))
-	writeLog(fmt.Sprintf("Files Skipped/No Change: %d", stats.FilesSkipped))
-	writeLog(fmt.Sprintf("Attributes Added: %d", stats.AttributesAdded))
-	writeLog(fmt.Sprintf("Attributes Replaced: %d", stats.AttributesReplaced))
-	writeLog(fmt.Sprintf("Errors Encountered: %d", stats.Errors))
-	writeLog("=" + strings.Repeat("=", 50))
+	logger.Log("=" + strings.Repeat("=", 50))
+	logger.Log("UPDATE SUMMARY")
+	logger.Log("=" + strings.Repeat("=", 50))
+	logger.Log(fmt.Sprintf("Execution Time: %v", duration))
+	logger.Log(fmt.Sprintf("Total Mappings Processed: %d", stats.TotalFiles))
+	logger.Log(fmt.Sprintf("Files Modified: %d", stats.FilesModified))
+	logger.Log(fmt.Sprintf("Files Skipped/No Change: %d", stats.FilesSkipped))
+	logger.Log(fmt.Sprintf("Attributes Added: %d", stats.AttributesAdded))
+	logger.Log(fmt.Sprintf("Attributes Replaced: %d", stats.AttributesReplaced))
+	logger.Log(fmt.Sprintf("Files Backed Up: %d (%d bytes)", stats.FilesBackedUp, stats.BytesBackedUp))
+	logger.Log(fmt.Sprintf("Errors Encountered: %d", stats.Errors))
+	logger.Log("=" + strings.Repeat("=", 50))
 }
 
-func runUpdater(csvPath, controllersDir string, preview, overwrite bool) {
+// backupBaseDir is where RunUpdater roots each run's timestamped backup tree.
+const backupBaseDir = "backups"
+
+// RunUpdater reads mapping rows from csvPath and applies their [Authorize]
+// attributes to the matching methods under controllersDir. Mode (preview,
+// overwrite or interactive) comes from the RunConfig on ctx. A cancelled
+// ctx (e.g. on SIGINT) is checked between mappings, so a run stops cleanly
+// between whole-file writes instead of mid-write.
+func RunUpdater(ctx context.Context, csvPath, controllersDir string) {
+	cfg := ConfigFromContext(ctx)
 	stats := UpdaterStats{StartTime: time.Now()}
 
-	writeLog("Starting Attribute Updater...")
-	writeLog(fmt.Sprintf("CSV File: %s", csvPath))
-	writeLog(fmt.Sprintf("Controllers Directory: %s", controllersDir))
-	if preview {
-		writeLog("Mode: PREVIEW - No files will be modified")
-	} else if overwrite {
-		writeLog("Mode: OVERWRITE - Existing attributes will be replaced automatically")
+	cfg.Logger.Log("Starting Attribute Updater...")
+	cfg.Logger.Log(fmt.Sprintf("CSV File: %s", csvPath))
+	cfg.Logger.Log(fmt.Sprintf("Controllers Directory: %s", controllersDir))
+	if cfg.Preview {
+		cfg.Logger.Log("Mode: PREVIEW - No files will be modified")
+	} else if cfg.Overwrite {
+		cfg.Logger.Log("Mode: OVERWRITE - Existing attributes will be replaced automatically")
 	} else {
-		writeLog("Mode: INTERACTIVE - Will prompt for confirmation on conflicts")
+		cfg.Logger.Log("Mode: INTERACTIVE - Will prompt for confirmation on conflicts")
+	}
+	cfg.Logger.Log("-" + strings.Repeat("-", 50))
+
+	var backup *backupRun
+	if !cfg.Preview {
+		br, err := newBackupRun(backupBaseDir)
+		if err != nil {
+			cfg.Logger.Log(fmt.Sprintf("WARNING: Could not set up backup directory: %v. Proceeding without backups.", err))
+		} else {
+			backup = br
+		}
 	}
-	writeLog("-" + strings.Repeat("-", 50))
 
-	mappings, err := readCsvMappings(csvPath)
+	mappings, err := ReadCsvMappings(ctx, csvPath)
 	if err != nil {
-		writeLog(fmt.Sprintf("FATAL ERROR: Failed to read CSV file '%s': %v", csvPath, err))
+		cfg.Logger.Log(fmt.Sprintf("FATAL ERROR: Failed to read CSV file '%s': %v", csvPath, err))
 		stats.Errors++
-		printUpdaterStats(stats)
+		printUpdaterStats(ctx, stats)
 		return
 	}
-	writeLog(fmt.Sprintf("Loaded %d mappings from CSV", len(mappings)))
+	cfg.Logger.Log(fmt.Sprintf("Loaded %d mappings from CSV", len(mappings)))
 
 	for _, mapping := range mappings {
+		if ctx.Err() != nil {
+			cfg.Logger.Log("⚠️  Update cancelled; stopping before the next file.")
+			break
+		}
+
 		stats.TotalFiles++
 		filePath := filepath.Join(controllersDir, mapping.Filename)
 
-		writeLogOnly(fmt.Sprintf("Processing file: %s, Method: %s", mapping.Filename, mapping.Method))
+		cfg.Logger.LogOnly(fmt.Sprintf("Processing file: %s, Method: %s", mapping.Filename, mapping.Method))
 
 		file, err := os.Open(filePath)
 		if err != nil {
-			writeLog(fmt.Sprintf("ERROR: Skipping file %s: %v", mapping.Filename, err))
+			cfg.Logger.Log(fmt.Sprintf("ERROR: Skipping file %s: %v", mapping.Filename, err))
 			stats.FilesSkipped++
 			stats.Errors++
 			continue
@@ -396,7 +521,7 @@ func runUpdater(csvPath, controllersDir string, preview, overwrite bool) {
 		file.Close()
 
 		if err := scanner.Err(); err != nil {
-			writeLog(fmt.Sprintf("ERROR: Reading file %s: %v", mapping.Filename, err))
+			cfg.Logger.Log(fmt.Sprintf("ERROR: Reading file %s: %v", mapping.Filename, err))
 			stats.FilesSkipped++
 			stats.Errors++
 			continue
@@ -417,7 +542,7 @@ func runUpdater(csvPath, controllersDir string, preview, overwrite bool) {
 					}
 					if strings.HasPrefix(trimmedLine, "[") {
 						attrBlockStartIndex = j
-						if isHttpAttribute(trimmedLine) {
+						if isHttpAttribute(trimmedLine, cfg.HTTPAttributes) {
 							httpAttrIndex = j
 						}
 						if isAuthorizeAttribute(trimmedLine) {
@@ -429,7 +554,7 @@ func runUpdater(csvPath, controllersDir string, preview, overwrite bool) {
 				}
 
 				if httpAttrIndex == -1 {
-					writeLog(fmt.Sprintf("WARNING: Could not find an HTTP attribute for method '%s' in '%s'. Skipping.", mapping.Method, mapping.Filename))
+					cfg.Logger.Log(fmt.Sprintf("WARNING: Could not find an HTTP attribute for method '%s' in '%s'. Skipping.", mapping.Method, mapping.Filename))
 					break
 				}
 				if attrBlockStartIndex == -1 {
@@ -447,38 +572,38 @@ func runUpdater(csvPath, controllersDir string, preview, overwrite bool) {
 				isAlreadyCorrect := len(authorizeIndices) == 1 && strings.TrimSpace(lines[authorizeIndices[0]]) == strings.TrimSpace(newAttrLine)
 
 				if isAlreadyCorrect {
-					writeLog(fmt.Sprintf("INFO: Attribute already correct for %s:%s.", mapping.Filename, mapping.Method))
+					cfg.Logger.Log(fmt.Sprintf("INFO: Attribute already correct for %s:%s.", mapping.Filename, mapping.Method))
 					break
 				}
 
 				if len(authorizeIndices) > 0 {
-					writeLog(fmt.Sprintf("📄 File: %s, 🔧 Method: %s", mapping.Filename, mapping.Method))
-					writeLog("🔁 Found existing attribute(s) to replace/clean up:")
+					cfg.Logger.Log(fmt.Sprintf("📄 File: %s, 🔧 Method: %s", mapping.Filename, mapping.Method))
+					cfg.Logger.Log("🔁 Found existing attribute(s) to replace/clean up:")
 					for k := len(authorizeIndices) - 1; k >= 0; k-- {
-						writeLog(fmt.Sprintf("   OLD: %s", strings.TrimSpace(lines[authorizeIndices[k]])))
+						cfg.Logger.Log(fmt.Sprintf("   OLD: %s", strings.TrimSpace(lines[authorizeIndices[k]])))
 					}
-					writeLog(fmt.Sprintf("   NEW: %s", strings.TrimSpace(newAttrLine)))
+					cfg.Logger.Log(fmt.Sprintf("   NEW: %s", strings.TrimSpace(newAttrLine)))
 				} else {
-					writeLog(fmt.Sprintf("📄 File: %s, 🔧 Method: %s", mapping.Filename, mapping.Method))
-					writeLog(fmt.Sprintf("➕ Inserting new attribute: %s", strings.TrimSpace(newAttrLine)))
+					cfg.Logger.Log(fmt.Sprintf("📄 File: %s, 🔧 Method: %s", mapping.Filename, mapping.Method))
+					cfg.Logger.Log(fmt.Sprintf("➕ Inserting new attribute: %s", strings.TrimSpace(newAttrLine)))
 				}
 
-				if preview {
-					writeLog("   [PREVIEW MODE] - No changes will be applied.")
+				if cfg.Preview {
+					cfg.Logger.Log("   [PREVIEW MODE] - No changes will be applied.")
 					break
 				}
 
 				var applyChange bool
-				if overwrite {
+				if cfg.Overwrite {
 					applyChange = true
-					writeLog("   👉 Applying change automatically due to --overwrite flag.")
+					cfg.Logger.Log("   👉 Applying change automatically due to --overwrite flag.")
 				} else {
 					// This is INTERACTIVE mode
 					applyChange = promptUser("❓ Do you want to apply this change? (y/N): ")
 					if applyChange {
-						writeLog("   ✅ User confirmed change.")
+						cfg.Logger.Log("   ✅ User confirmed change.")
 					} else {
-						writeLog("   ❌ User declined change.")
+						cfg.Logger.Log("   ❌ User declined change.")
 					}
 				}
 
@@ -508,35 +633,57 @@ func runUpdater(csvPath, controllersDir string, preview, overwrite bool) {
 		}
 
 		if !methodFound {
-			writeLog(fmt.Sprintf("WARNING: Method '%s' not found in file '%s'", mapping.Method, mapping.Filename))
+			cfg.Logger.Log(fmt.Sprintf("WARNING: Method '%s' not found in file '%s'", mapping.Method, mapping.Filename))
 			stats.FilesSkipped++
 		}
 
-		if fileModified && !preview {
+		if fileModified && !cfg.Preview {
+			if backup != nil {
+				bytesWritten, err := backup.backupFile(mapping.Filename, filePath)
+				if err != nil {
+					cfg.Logger.Log(fmt.Sprintf("WARNING: Could not back up %s before modifying it: %v", mapping.Filename, err))
+				} else if bytesWritten > 0 {
+					stats.FilesBackedUp++
+					stats.BytesBackedUp += bytesWritten
+				}
+			}
+
 			output := strings.Join(lines, "\n")
 			// Ensure file ends with a newline
 			if !strings.HasSuffix(output, "\n") {
 				output += "\n"
 			}
-			err := os.WriteFile(filePath, []byte(output), 0644)
+			outputBytes := []byte(output)
+			err := os.WriteFile(filePath, outputBytes, 0644)
 			if err != nil {
-				writeLog(fmt.Sprintf("ERROR: Writing updated file %s: %v", filePath, err))
+				cfg.Logger.Log(fmt.Sprintf("ERROR: Writing updated file %s: %v", filePath, err))
 				stats.Errors++
 				continue
 			}
-			writeLog(fmt.Sprintf("✅ Successfully updated: %s", mapping.Filename))
+			if backup != nil {
+				backup.recordUpdate(filePath, outputBytes)
+			}
+			cfg.Logger.Log(fmt.Sprintf("✅ Successfully updated: %s", mapping.Filename))
 			stats.FilesModified++
 		} else if !fileModified && methodFound {
 			stats.FilesSkipped++
 		}
 	}
 
-	if preview {
-		writeLog("✨ Preview complete. No files were modified.")
+	if cfg.Preview {
+		cfg.Logger.Log("✨ Preview complete. No files were modified.")
 	} else {
-		writeLog("✨ All operations complete.")
+		cfg.Logger.Log("✨ All operations complete.")
+		if backup != nil && len(backup.entries) > 0 {
+			manifestPath, err := backup.writeManifest(controllersDir)
+			if err != nil {
+				cfg.Logger.Log(fmt.Sprintf("WARNING: Could not write backup manifest: %v", err))
+			} else {
+				cfg.Logger.Log(fmt.Sprintf("🗄️  Backup manifest written to: %s", manifestPath))
+			}
+		}
 	}
-	printUpdaterStats(stats)
+	printUpdaterStats(ctx, stats)
 }
 
 func printBanner() {
@@ -546,7 +693,7 @@ func printBanner() {
 	fmt.Println()
 }
 
-func handleScan() {
+func handleScan(ctx context.Context) {
 	fmt.Println("\n--- Scan Controllers ---")
 	fmt.Println("This tool scans C# controller files and generates a CSV")
 	fmt.Println("template with all HTTP methods found.")
@@ -564,10 +711,10 @@ func handleScan() {
 	}
 
 	fmt.Println()
-	runScanner(controllersDir, outputCsv)
+	RunScanner(ctx, controllersDir, outputCsv)
 }
 
-func handleUpdate() {
+func handleUpdate(ctx context.Context) {
 	fmt.Println("\n--- Update Attributes from CSV ---")
 	fmt.Println("This tool reads a CSV file to update C# controller methods")
 	fmt.Println("with [Authorize] attributes.")
@@ -590,45 +737,57 @@ func handleUpdate() {
 	fmt.Println("  3. Preview (Show changes without modifying files)")
 	modeChoice := promptForInput("Enter choice (1-3): ")
 
-	var isPreview, isOverwrite bool
+	cfg := ConfigFromContext(ctx)
 	switch modeChoice {
 	case "2":
-		isOverwrite = true
+		cfg.Overwrite = true
 	case "3":
-		isPreview = true
+		cfg.Preview = true
 	default:
 	}
 
 	fmt.Println()
-	runUpdater(csvPath, controllersDir, isPreview, isOverwrite)
+	RunUpdater(WithConfig(ctx, cfg), csvPath, controllersDir)
 }
 
-func main() {
-	setupLogger()
-	defer closeLogger()
-
+// runInteractive drives the old prompt-loop menu. It's what the root cobra
+// command falls back to when invoked with no subcommand.
+func runInteractive(ctx context.Context) {
 	for {
 		printBanner()
 		fmt.Println("What would you like to do?")
 		fmt.Println("  1. Scan Controllers to generate a CSV")
 		fmt.Println("  2. Update Controllers from a CSV")
-		fmt.Println("  3. Exit")
+		fmt.Println("  3. Restore from a backup manifest")
+		fmt.Println("  4. Exit")
 
-		choice := promptForInput("\nEnter your choice (1-3): ")
+		choice := promptForInput("\nEnter your choice (1-4): ")
 
 		switch choice {
 		case "1":
-			handleScan()
+			handleScan(ctx)
 		case "2":
-			handleUpdate()
+			handleUpdate(ctx)
 		case "3":
+			handleRestore(ctx)
+		case "4":
 			fmt.Println("\nExiting Migro. Goodbye!")
 			return
 		default:
-			fmt.Println("\nInvalid choice. Please enter 1, 2, or 3.")
+			fmt.Println("\nInvalid choice. Please enter 1, 2, 3, or 4.")
 		}
 
 		promptForInput("\nPress Enter to return to the main menu...")
 		fmt.Print("\033[H\033[2J")
 	}
 }
+
+func main() {
+	ctx, stop := signalContext()
+	defer stop()
+
+	if err := newRootCmd().ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
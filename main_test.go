@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testContext() context.Context {
+	return WithConfig(context.Background(), DefaultRunConfig())
+}
+
+func TestScanControllerFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "WidgetsController.cs")
+	content := `namespace Api.Controllers
+{
+    public class WidgetsController
+    {
+        [HttpGet]
+        [Authorize(Roles = "Admin")]
+        public IActionResult GetAll()
+        {
+            return Ok();
+        }
+
+        [HttpPost]
+        public IActionResult Create()
+        {
+            return Ok();
+        }
+    }
+}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := &ScannerStats{}
+	methods, err := ScanControllerFile(testContext(), path, stats)
+	if err != nil {
+		t.Fatalf("ScanControllerFile: %v", err)
+	}
+	if len(methods) != 2 {
+		t.Fatalf("got %d methods, want 2", len(methods))
+	}
+
+	byMethod := map[string]string{}
+	for _, m := range methods {
+		byMethod[m.Method] = m.Attribute
+	}
+	if got := byMethod["GetAll"]; got != `[Authorize(Roles = "Admin")]` {
+		t.Errorf("GetAll attribute = %q", got)
+	}
+	if got, want := byMethod["Create"], DefaultRunConfig().DefaultAuthorizeAttr; got != want {
+		t.Errorf("Create attribute = %q, want default %q", got, want)
+	}
+	if stats.MethodsFound != 2 {
+		t.Errorf("MethodsFound = %d, want 2", stats.MethodsFound)
+	}
+}
+
+// TestRunUpdaterAddsAndReplacesAttributes exercises the business logic that
+// used to be reachable only through the interactive menu's stdin prompts:
+// with cfg.Overwrite set, RunUpdater should apply every mapping without
+// asking for confirmation.
+func TestRunUpdaterAddsAndReplacesAttributes(t *testing.T) {
+	dir := t.TempDir()
+	controllerPath := filepath.Join(dir, "WidgetsController.cs")
+	content := `namespace Api.Controllers
+{
+    public class WidgetsController
+    {
+        [HttpGet]
+        public IActionResult GetAll()
+        {
+            return Ok();
+        }
+
+        [HttpPost]
+        [Authorize(Roles = "Old")]
+        public IActionResult Create()
+        {
+            return Ok();
+        }
+    }
+}
+`
+	if err := os.WriteFile(controllerPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	csvPath := filepath.Join(dir, "mappings.csv")
+	csvContent := "filename,controller,method,attribute\n" +
+		`WidgetsController.cs,WidgetsController,GetAll,"[Authorize(Roles = ""New"")]"` + "\n" +
+		`WidgetsController.cs,WidgetsController,Create,"[Authorize(Roles = ""New"")]"` + "\n"
+	if err := os.WriteFile(csvPath, []byte(csvContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// RunUpdater writes its backup manifest under "backups" relative to the
+	// working directory; run from the scratch dir so it doesn't land in the repo.
+	origWD, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origWD)
+
+	cfg := DefaultRunConfig()
+	cfg.Overwrite = true
+	RunUpdater(WithConfig(context.Background(), cfg), "mappings.csv", ".")
+
+	updated, err := os.ReadFile(controllerPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(updated)
+	if strings.Count(out, `[Authorize(Roles = "New")]`) != 2 {
+		t.Fatalf("expected both methods updated to the new attribute, got:\n%s", out)
+	}
+	if strings.Contains(out, `[Authorize(Roles = "Old")]`) {
+		t.Fatalf("old attribute should have been replaced, got:\n%s", out)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// RestorerStats mirrors ScannerStats/UpdaterStats so restore runs print a
+// summary in the same shape as the other operations.
+type RestorerStats struct {
+	TotalEntries  int
+	FilesRestored int
+	FilesSkipped  int
+	Errors        int
+	StartTime     time.Time
+}
+
+func readManifest(manifestPath string) (*BackupManifest, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	var manifest BackupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+func printRestorerStats(ctx context.Context, stats RestorerStats) {
+	logger := ConfigFromContext(ctx).Logger
+	duration := time.Since(stats.StartTime)
+	logger.Log("=" + strings.Repeat("=", 50))
+	logger.Log("RESTORE SUMMARY")
+	logger.Log("=" + strings.Repeat("=", 50))
+	logger.Log(fmt.Sprintf("Execution Time: %v", duration))
+	logger.Log(fmt.Sprintf("Total Manifest Entries: %d", stats.TotalEntries))
+	logger.Log(fmt.Sprintf("Files Restored: %d", stats.FilesRestored))
+	logger.Log(fmt.Sprintf("Files Skipped: %d", stats.FilesSkipped))
+	logger.Log(fmt.Sprintf("Errors Encountered: %d", stats.Errors))
+	logger.Log("=" + strings.Repeat("=", 50))
+}
+
+// RunRestorer walks every entry in the manifest at manifestPath and copies
+// the backed-up original back over its source path. Before overwriting, it
+// hashes the file as it currently stands: if that hash doesn't match what
+// the updater wrote (UpdatedSHA256), the file was hand-edited since, and the
+// entry is skipped rather than clobbered. ctx is checked between entries so
+// a SIGINT stops the restore between whole-file writes.
+func RunRestorer(ctx context.Context, manifestPath string) {
+	logger := ConfigFromContext(ctx).Logger
+	stats := RestorerStats{StartTime: time.Now()}
+
+	logger.Log("Starting Restorer...")
+	logger.Log(fmt.Sprintf("Manifest File: %s", manifestPath))
+	logger.Log("-" + strings.Repeat("-", 50))
+
+	manifest, err := readManifest(manifestPath)
+	if err != nil {
+		logger.Log(fmt.Sprintf("FATAL ERROR: Failed to read manifest '%s': %v", manifestPath, err))
+		stats.Errors++
+		printRestorerStats(ctx, stats)
+		return
+	}
+	stats.TotalEntries = len(manifest.Entries)
+	logger.Log(fmt.Sprintf("Loaded %d entries from manifest", stats.TotalEntries))
+
+	for _, entry := range manifest.Entries {
+		if ctx.Err() != nil {
+			logger.Log("⚠️  Restore cancelled; stopping before the next file.")
+			break
+		}
+
+		current, err := os.ReadFile(entry.SourcePath)
+		if err != nil {
+			logger.Log(fmt.Sprintf("ERROR: Reading current file %s: %v", entry.SourcePath, err))
+			stats.Errors++
+			continue
+		}
+
+		currentHash := sha256Hex(current)
+		if currentHash == entry.OriginalSHA256 {
+			logger.Log(fmt.Sprintf("INFO: %s already matches the original. Skipping.", entry.SourcePath))
+			stats.FilesSkipped++
+			continue
+		}
+		if entry.UpdatedSHA256 != "" && currentHash != entry.UpdatedSHA256 {
+			logger.Log(fmt.Sprintf("WARNING: %s has changed since the update ran (hand-edited?). Skipping to avoid clobbering it.", entry.SourcePath))
+			stats.FilesSkipped++
+			continue
+		}
+
+		backupData, err := os.ReadFile(entry.BackupPath)
+		if err != nil {
+			logger.Log(fmt.Sprintf("ERROR: Reading backup %s: %v", entry.BackupPath, err))
+			stats.Errors++
+			continue
+		}
+
+		if err := os.WriteFile(entry.SourcePath, backupData, 0644); err != nil {
+			logger.Log(fmt.Sprintf("ERROR: Restoring %s: %v", entry.SourcePath, err))
+			stats.Errors++
+			continue
+		}
+
+		logger.Log(fmt.Sprintf("✅ Restored: %s", entry.SourcePath))
+		stats.FilesRestored++
+	}
+
+	logger.Log("✨ Restore complete.")
+	printRestorerStats(ctx, stats)
+}
+
+func handleRestore(ctx context.Context) {
+	fmt.Println("\n--- Restore from Backup ---")
+	fmt.Println("This tool reads a backup manifest and restores the original")
+	fmt.Println("files it recorded, skipping any that were hand-edited since.")
+	fmt.Println()
+
+	manifestPath := promptForInput("Enter the path to the backup manifest.json: ")
+	if manifestPath == "" {
+		fmt.Println("Path cannot be empty. Aborting.")
+		return
+	}
+
+	fmt.Println()
+	RunRestorer(ctx, manifestPath)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupBackupAndUpdate backs up originalContent, then simulates an updater
+// run writing updatedContent over it, returning the manifest path the way
+// RunUpdater would leave it for a later restore.
+func setupBackupAndUpdate(t *testing.T, dir string, originalContent, updatedContent []byte) (manifestPath, filePath string) {
+	t.Helper()
+
+	filePath = filepath.Join(dir, "WidgetsController.cs")
+	if err := os.WriteFile(filePath, originalContent, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	backupDir := filepath.Join(dir, "backups")
+	run, err := newBackupRun(backupDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run.backupFile("WidgetsController.cs", filePath); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filePath, updatedContent, 0644); err != nil {
+		t.Fatal(err)
+	}
+	run.recordUpdate(filePath, updatedContent)
+
+	manifestPath, err = run.writeManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manifestPath, filePath
+}
+
+func TestRunRestorerRestoresUntouchedFile(t *testing.T) {
+	dir := t.TempDir()
+	original := []byte("original contents")
+	updated := []byte("updated contents")
+	manifestPath, filePath := setupBackupAndUpdate(t, dir, original, updated)
+
+	RunRestorer(testContext(), manifestPath)
+
+	got, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(original) {
+		t.Errorf("file content = %q, want original %q", got, original)
+	}
+}
+
+func TestRunRestorerSkipsHandEditedFile(t *testing.T) {
+	dir := t.TempDir()
+	original := []byte("original contents")
+	updated := []byte("updated contents")
+	manifestPath, filePath := setupBackupAndUpdate(t, dir, original, updated)
+
+	handEdited := []byte("someone changed this after the update ran")
+	if err := os.WriteFile(filePath, handEdited, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	RunRestorer(testContext(), manifestPath)
+
+	got, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(handEdited) {
+		t.Errorf("hand-edited file was overwritten; content = %q, want untouched %q", got, handEdited)
+	}
+}
+
+func TestRunRestorerSkipsAlreadyOriginalFile(t *testing.T) {
+	dir := t.TempDir()
+	original := []byte("original contents")
+	updated := []byte("updated contents")
+	manifestPath, filePath := setupBackupAndUpdate(t, dir, original, updated)
+
+	// Someone already restored it (or the update never actually changed it).
+	if err := os.WriteFile(filePath, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	RunRestorer(testContext(), manifestPath)
+
+	got, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(original) {
+		t.Errorf("file content changed unexpectedly; got %q", got)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestScanDirectoryWithMultipleWorkers is the worker-pool path's regression
+// test: run with `go test -race` so a future change that reintroduces a data
+// race on ScannerStats or deadlocks the jobs/resultsCh fan-in gets caught.
+func TestScanDirectoryWithMultipleWorkers(t *testing.T) {
+	dir := t.TempDir()
+	const fileCount = 20
+	for i := 0; i < fileCount; i++ {
+		content := fmt.Sprintf(`namespace Api.Controllers
+{
+    public class Widget%dController
+    {
+        [HttpGet]
+        public IActionResult Get()
+        {
+            return Ok();
+        }
+    }
+}
+`, i)
+		path := filepath.Join(dir, fmt.Sprintf("Widget%dController.cs", i))
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A non-.cs file should be skipped rather than scanned.
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a controller"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultRunConfig()
+	cfg.Workers = 4
+	ctx := WithConfig(testContext(), cfg)
+
+	stats := &ScannerStats{}
+	methods, err := scanDirectory(ctx, dir, stats)
+	if err != nil {
+		t.Fatalf("scanDirectory: %v", err)
+	}
+	if len(methods) != fileCount {
+		t.Fatalf("got %d methods, want %d", len(methods), fileCount)
+	}
+	if stats.TotalFiles != fileCount {
+		t.Errorf("TotalFiles = %d, want %d", stats.TotalFiles, fileCount)
+	}
+	if stats.FilesProcessed != fileCount {
+		t.Errorf("FilesProcessed = %d, want %d", stats.FilesProcessed, fileCount)
+	}
+	if stats.MethodsFound != fileCount {
+		t.Errorf("MethodsFound = %d, want %d", stats.MethodsFound, fileCount)
+	}
+	if stats.Errors != 0 {
+		t.Errorf("Errors = %d, want 0", stats.Errors)
+	}
+}